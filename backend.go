@@ -0,0 +1,79 @@
+package lazymap
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"time"
+)
+
+// Backend persists a Map's values outside the process so they survive a
+// restart. A Map configured via WithBackend consults it before running the
+// constructor on a miss, and keeps it in sync whenever a value is
+// constructed or deleted.
+type Backend[K comparable, V any] interface {
+	// Get returns the persisted value for key along with its remaining TTL
+	// (zero meaning unlimited) and whether it was found at all.
+	Get(ctx context.Context, key K) (val V, ttl time.Duration, ok bool, err error)
+	// Put persists val for key with the given remaining TTL (zero meaning
+	// unlimited).
+	Put(ctx context.Context, key K, val V, ttl time.Duration) error
+	// Delete removes key from the backend, if present.
+	Delete(ctx context.Context, key K) error
+	// Iterate calls fn with every persisted key, stopping early if fn
+	// returns false.
+	Iterate(ctx context.Context, fn func(key K) bool) error
+}
+
+// Codec marshals and unmarshals values of type V for a Backend.
+type Codec[V any] interface {
+	Encode(V) ([]byte, error)
+	Decode([]byte) (V, error)
+}
+
+// KeyCodec turns a key into a string a Backend can use to address it (e.g. a
+// file name or database key), and back.
+type KeyCodec[K comparable] interface {
+	Encode(K) string
+	Decode(string) (K, error)
+}
+
+// GobCodec is the common-case Codec: it marshals values with encoding/gob.
+type GobCodec[V any] struct{}
+
+// Encode implements Codec.
+func (GobCodec[V]) Encode(v V) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode implements Codec.
+func (GobCodec[V]) Decode(b []byte) (V, error) {
+	var v V
+	err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v)
+	return v, err
+}
+
+// StringKeyCodec is the common-case KeyCodec, for string keys.
+type StringKeyCodec struct{}
+
+// Encode implements KeyCodec.
+func (StringKeyCodec) Encode(k string) string { return k }
+
+// Decode implements KeyCodec.
+func (StringKeyCodec) Decode(s string) (string, error) { return s, nil }
+
+// Option configures a Map at construction time. See WithBackend.
+type Option[K comparable, V any] func(*Map[K, V])
+
+// WithBackend makes m consult backend before running the constructor on a
+// miss, and keeps it in sync with Put/Delete so cached values - and their
+// TTL - survive process restarts.
+func WithBackend[K comparable, V any](backend Backend[K, V]) Option[K, V] {
+	return func(m *Map[K, V]) {
+		m.backend = backend
+	}
+}
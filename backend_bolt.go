@@ -0,0 +1,137 @@
+//go:build lazymap_boltdb
+
+package lazymap
+
+// BoltBackend is a Backend backed by a BoltDB (go.etcd.io/bbolt) database
+// file. It is excluded from the default build so this module stays
+// dependency-free unless you opt in: build with -tags lazymap_boltdb after
+// `go get go.etcd.io/bbolt`.
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var boltBucket = []byte("lazymap")
+
+// errStopBoltIteration unwinds out of ForEach once fn asks Iterate to stop;
+// it never escapes Iterate itself.
+var errStopBoltIteration = errors.New("lazymap: stop bolt iteration")
+
+// BoltBackend is a Backend backed by a BoltDB database file.
+type BoltBackend[K comparable, V any] struct {
+	db       *bolt.DB
+	codec    Codec[V]
+	keyCodec KeyCodec[K]
+}
+
+// NewBoltBackend opens (creating if needed) a BoltDB database at path and
+// returns a Backend backed by it.
+func NewBoltBackend[K comparable, V any](path string, codec Codec[V], keyCodec KeyCodec[K]) (*BoltBackend[K, V], error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltBackend[K, V]{db: db, codec: codec, keyCodec: keyCodec}, nil
+}
+
+// Close closes the underlying database.
+func (b *BoltBackend[K, V]) Close() error {
+	return b.db.Close()
+}
+
+// Get implements Backend.
+func (b *BoltBackend[K, V]) Get(_ context.Context, key K) (val V, ttl time.Duration, ok bool, err error) {
+	err = b.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(boltBucket).Get([]byte(b.keyCodec.Encode(key)))
+		if raw == nil {
+			return nil
+		}
+
+		var rec fsRecord
+		if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&rec); err != nil {
+			return err
+		}
+		if !rec.Deadline.IsZero() {
+			remaining := time.Until(rec.Deadline)
+			if remaining <= 0 {
+				return nil
+			}
+			ttl = remaining
+		}
+		val, err = b.codec.Decode(rec.Value)
+		if err != nil {
+			return err
+		}
+		ok = true
+		return nil
+	})
+	if err != nil {
+		return val, 0, false, err
+	}
+	if !ok {
+		return val, 0, false, nil
+	}
+	return val, ttl, true, nil
+}
+
+// Put implements Backend.
+func (b *BoltBackend[K, V]) Put(_ context.Context, key K, val V, ttl time.Duration) error {
+	encoded, err := b.codec.Encode(val)
+	if err != nil {
+		return err
+	}
+
+	rec := fsRecord{Value: encoded}
+	if ttl > 0 {
+		rec.Deadline = time.Now().Add(ttl)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(rec); err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(b.keyCodec.Encode(key)), buf.Bytes())
+	})
+}
+
+// Delete implements Backend.
+func (b *BoltBackend[K, V]) Delete(_ context.Context, key K) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(b.keyCodec.Encode(key)))
+	})
+}
+
+// Iterate implements Backend.
+func (b *BoltBackend[K, V]) Iterate(_ context.Context, fn func(key K) bool) error {
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).ForEach(func(k, _ []byte) error {
+			key, err := b.keyCodec.Decode(string(k))
+			if err != nil {
+				return nil
+			}
+			if !fn(key) {
+				return errStopBoltIteration
+			}
+			return nil
+		})
+	})
+	if errors.Is(err, errStopBoltIteration) {
+		return nil
+	}
+	return err
+}
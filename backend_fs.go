@@ -0,0 +1,136 @@
+package lazymap
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FSBackend is a Backend that persists each key as a gob-encoded file under
+// a base directory, so cached values and their remaining TTL survive a
+// process restart.
+type FSBackend[K comparable, V any] struct {
+	dir      string
+	codec    Codec[V]
+	keyCodec KeyCodec[K]
+}
+
+// fsRecord is what's actually written to disk: the encoded value plus the
+// absolute deadline it was stored with, so remaining TTL survives a restart.
+type fsRecord struct {
+	Value    []byte
+	Deadline time.Time // zero means unlimited
+}
+
+const fsTempPrefix = ".tmp-"
+
+// NewFSBackend returns an FSBackend that stores entries as gob-encoded files
+// under dir, creating it if it doesn't exist. codec marshals the values and
+// keyCodec turns keys into file names; pass GobCodec[V]{} and
+// StringKeyCodec{} for the common case of gob-encodable values with string
+// keys.
+func NewFSBackend[K comparable, V any](dir string, codec Codec[V], keyCodec KeyCodec[K]) (*FSBackend[K, V], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FSBackend[K, V]{dir: dir, codec: codec, keyCodec: keyCodec}, nil
+}
+
+func (b *FSBackend[K, V]) path(key K) string {
+	return filepath.Join(b.dir, b.keyCodec.Encode(key))
+}
+
+// Get implements Backend.
+func (b *FSBackend[K, V]) Get(_ context.Context, key K) (val V, ttl time.Duration, ok bool, err error) {
+	f, err := os.Open(b.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return val, 0, false, nil
+	}
+	if err != nil {
+		return val, 0, false, err
+	}
+	defer f.Close()
+
+	var rec fsRecord
+	if err := gob.NewDecoder(f).Decode(&rec); err != nil {
+		return val, 0, false, err
+	}
+
+	if !rec.Deadline.IsZero() {
+		remaining := time.Until(rec.Deadline)
+		if remaining <= 0 {
+			_ = b.Delete(context.Background(), key)
+			return val, 0, false, nil
+		}
+		ttl = remaining
+	}
+
+	val, err = b.codec.Decode(rec.Value)
+	if err != nil {
+		return val, 0, false, err
+	}
+	return val, ttl, true, nil
+}
+
+// Put implements Backend. It writes via a temp file and rename so a reader
+// never observes a partially-written record.
+func (b *FSBackend[K, V]) Put(_ context.Context, key K, val V, ttl time.Duration) error {
+	encoded, err := b.codec.Encode(val)
+	if err != nil {
+		return err
+	}
+
+	rec := fsRecord{Value: encoded}
+	if ttl > 0 {
+		rec.Deadline = time.Now().Add(ttl)
+	}
+
+	tmp, err := os.CreateTemp(b.dir, fsTempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(tmp).Encode(rec); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), b.path(key))
+}
+
+// Delete implements Backend.
+func (b *FSBackend[K, V]) Delete(_ context.Context, key K) error {
+	err := os.Remove(b.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Iterate implements Backend.
+func (b *FSBackend[K, V]) Iterate(_ context.Context, fn func(key K) bool) error {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), fsTempPrefix) {
+			continue
+		}
+		key, err := b.keyCodec.Decode(entry.Name())
+		if err != nil {
+			continue
+		}
+		if !fn(key) {
+			break
+		}
+	}
+	return nil
+}
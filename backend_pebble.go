@@ -0,0 +1,116 @@
+//go:build lazymap_pebble
+
+package lazymap
+
+// PebbleBackend is a Backend backed by a Pebble (github.com/cockroachdb/pebble)
+// key-value store. It is excluded from the default build so this module
+// stays dependency-free unless you opt in: build with -tags lazymap_pebble
+// after `go get github.com/cockroachdb/pebble`.
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// PebbleBackend is a Backend backed by a Pebble database directory.
+type PebbleBackend[K comparable, V any] struct {
+	db       *pebble.DB
+	codec    Codec[V]
+	keyCodec KeyCodec[K]
+}
+
+// NewPebbleBackend opens (creating if needed) a Pebble database at dir and
+// returns a Backend backed by it.
+func NewPebbleBackend[K comparable, V any](dir string, codec Codec[V], keyCodec KeyCodec[K]) (*PebbleBackend[K, V], error) {
+	db, err := pebble.Open(dir, &pebble.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return &PebbleBackend[K, V]{db: db, codec: codec, keyCodec: keyCodec}, nil
+}
+
+// Close closes the underlying database.
+func (b *PebbleBackend[K, V]) Close() error {
+	return b.db.Close()
+}
+
+// Get implements Backend.
+func (b *PebbleBackend[K, V]) Get(_ context.Context, key K) (val V, ttl time.Duration, ok bool, err error) {
+	raw, closer, err := b.db.Get([]byte(b.keyCodec.Encode(key)))
+	if err == pebble.ErrNotFound {
+		return val, 0, false, nil
+	}
+	if err != nil {
+		return val, 0, false, err
+	}
+	defer closer.Close()
+
+	var rec fsRecord
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&rec); err != nil {
+		return val, 0, false, err
+	}
+
+	if !rec.Deadline.IsZero() {
+		remaining := time.Until(rec.Deadline)
+		if remaining <= 0 {
+			_ = b.Delete(context.Background(), key)
+			return val, 0, false, nil
+		}
+		ttl = remaining
+	}
+
+	val, err = b.codec.Decode(rec.Value)
+	if err != nil {
+		return val, 0, false, err
+	}
+	return val, ttl, true, nil
+}
+
+// Put implements Backend.
+func (b *PebbleBackend[K, V]) Put(_ context.Context, key K, val V, ttl time.Duration) error {
+	encoded, err := b.codec.Encode(val)
+	if err != nil {
+		return err
+	}
+
+	rec := fsRecord{Value: encoded}
+	if ttl > 0 {
+		rec.Deadline = time.Now().Add(ttl)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(rec); err != nil {
+		return err
+	}
+
+	return b.db.Set([]byte(b.keyCodec.Encode(key)), buf.Bytes(), pebble.Sync)
+}
+
+// Delete implements Backend.
+func (b *PebbleBackend[K, V]) Delete(_ context.Context, key K) error {
+	return b.db.Delete([]byte(b.keyCodec.Encode(key)), pebble.Sync)
+}
+
+// Iterate implements Backend.
+func (b *PebbleBackend[K, V]) Iterate(_ context.Context, fn func(key K) bool) error {
+	iter, err := b.db.NewIter(&pebble.IterOptions{})
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for iter.First(); iter.Valid(); iter.Next() {
+		key, err := b.keyCodec.Decode(string(iter.Key()))
+		if err != nil {
+			continue
+		}
+		if !fn(key) {
+			break
+		}
+	}
+	return iter.Error()
+}
@@ -0,0 +1,48 @@
+package lazymap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/waylen888/lazymap"
+)
+
+// Test_Backend_RoundTripsAndSyncsOnEviction exercises a Map configured with
+// both a Backend and an EvictionPolicy together, the combination chunk0-5
+// and chunk0-2 were each written to support (a bounded cache of persisted
+// values). It checks two things: a constructed value actually round-trips
+// through the backend, and evicting a key also removes it from the backend
+// so a later miss can't resurrect the "evicted" value.
+func Test_Backend_RoundTripsAndSyncsOnEviction(t *testing.T) {
+	backend, err := lazymap.NewFSBackend[string, string](t.TempDir(), lazymap.GobCodec[string]{}, lazymap.StringKeyCodec{})
+	if err != nil {
+		t.Fatalf("NewFSBackend: %v", err)
+	}
+
+	m := lazymap.New[string, string](0, lazymap.WithBackend[string, string](backend))
+	m.EvictionPolicy = lazymap.NewLRU[string, string](2)
+
+	ctor := func(_ context.Context, key string) (string, error) {
+		return "value-" + key, nil
+	}
+
+	for _, key := range []string{"a", "b"} {
+		if _, err := m.LoadOrCtor(context.Background(), key, ctor); err != nil {
+			t.Fatalf("LoadOrCtor %s: %v", key, err)
+		}
+	}
+
+	if val, _, ok, err := backend.Get(context.Background(), "a"); err != nil || !ok || val != "value-a" {
+		t.Fatalf("backend round-trip for %q: val=%q ok=%v err=%v", "a", val, ok, err)
+	}
+
+	// Inserting a third key evicts "a" (LRU, capacity 2): the backend entry
+	// must go with it.
+	if _, err := m.LoadOrCtor(context.Background(), "c", ctor); err != nil {
+		t.Fatalf("LoadOrCtor c: %v", err)
+	}
+
+	if _, _, ok, err := backend.Get(context.Background(), "a"); err != nil || ok {
+		t.Fatalf("evicted key %q still present in backend: ok=%v err=%v", "a", ok, err)
+	}
+}
@@ -0,0 +1,92 @@
+package lazymap
+
+import "time"
+
+// EventKind categorizes an Event.
+type EventKind int
+
+const (
+	// Loaded fires when a new entry gets its value, either from a successful
+	// constructor call or from the Backend.
+	Loaded EventKind = iota
+	// Hit fires when a call is served from an already-constructed entry.
+	Hit
+	// Refreshed fires when RefreshAheadFraction or StaleFor replace an
+	// entry's value in the background.
+	Refreshed
+	// Expired fires when an entry is removed because its TTL elapsed.
+	Expired
+	// Deleted fires when an entry is removed via Delete.
+	Deleted
+	// Errored fires when a construction ends in an error. With a
+	// RetryPolicy, it fires once for the final error, not for each failed
+	// attempt.
+	Errored
+)
+
+// Event describes something that happened to a key in a Map.
+type Event[K comparable, V any] struct {
+	Kind  EventKind
+	Key   K
+	Value V
+	Time  time.Time
+}
+
+// eventBufferSize is the per-subscriber channel buffer. Once full, further
+// events for that subscriber are dropped rather than blocking the Map.
+const eventBufferSize = 16
+
+// Subscribe returns a channel that receives every lifecycle Event for this
+// Map. If the subscriber falls behind, events are dropped (see
+// Stats.DroppedEvents) instead of blocking the Map. The channel is never
+// closed (see Unsubscribe); the caller should simply stop reading from it.
+func (m *Map[K, V]) Subscribe() <-chan Event[K, V] {
+	ch := make(chan Event[K, V], eventBufferSize)
+	m.eventMu.Lock()
+	if m.subscribers == nil {
+		m.subscribers = make(map[chan Event[K, V]]struct{})
+	}
+	m.subscribers[ch] = struct{}{}
+	m.eventMu.Unlock()
+	return ch
+}
+
+// Unsubscribe stops ch from receiving further events. ch must have come from
+// Subscribe on the same Map. ch is not closed: emit may already hold a
+// reference to it and could be sending concurrently, so closing here could
+// race a send on a closed channel. The channel is left for the garbage
+// collector once the caller drops its own reference.
+func (m *Map[K, V]) Unsubscribe(ch <-chan Event[K, V]) {
+	m.eventMu.Lock()
+	for c := range m.subscribers {
+		if c == ch {
+			delete(m.subscribers, c)
+			break
+		}
+	}
+	m.eventMu.Unlock()
+}
+
+// emit fans out an event to every subscriber, dropping it for any that
+// aren't keeping up.
+func (m *Map[K, V]) emit(kind EventKind, key K, val V) {
+	m.eventMu.Lock()
+	if len(m.subscribers) == 0 {
+		m.eventMu.Unlock()
+		return
+	}
+	subs := make([]chan Event[K, V], 0, len(m.subscribers))
+	for c := range m.subscribers {
+		subs = append(subs, c)
+	}
+	m.eventMu.Unlock()
+
+	ev := Event[K, V]{Kind: kind, Key: key, Value: val, Time: time.Now()}
+	for _, c := range subs {
+		select {
+		case c <- ev:
+		default:
+			m.stats.droppedEvents.Add(1)
+		}
+	}
+}
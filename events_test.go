@@ -0,0 +1,59 @@
+package lazymap_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/waylen888/lazymap"
+)
+
+// Test_Events_UnsubscribeDuringEmit drives concurrent Subscribe/Unsubscribe
+// against a steady stream of emitted events. It reproduces a prior data race
+// where emit held a subscriber channel captured before releasing eventMu,
+// while Unsubscribe closed that same channel under the lock: a send landing
+// after the close panicked with "send on closed channel". Run with -race to
+// catch the underlying unsynchronized close/send as well.
+func Test_Events_UnsubscribeDuringEmit(t *testing.T) {
+	m := lazymap.New[string, int](0)
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+
+	// Steady stream of events via repeated LoadOrCtor/Delete.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		i := 0
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			i++
+			_, _ = m.LoadOrCtor(context.Background(), "k", func(ctx context.Context, _ string) (int, error) {
+				return i, nil
+			})
+			m.Delete("k")
+		}
+	}()
+
+	// Concurrently subscribe and immediately unsubscribe, racing emit's
+	// copy-then-send against Unsubscribe's delete for the same channel.
+	var churn sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		churn.Add(1)
+		go func() {
+			defer churn.Done()
+			for j := 0; j < 500; j++ {
+				ch := m.Subscribe()
+				m.Unsubscribe(ch)
+			}
+		}()
+	}
+
+	churn.Wait()
+	close(stop)
+	wg.Wait()
+}
@@ -0,0 +1,175 @@
+package lazymap
+
+import "container/list"
+
+// EvictionPolicy decides which key a Map should evict to make room for a
+// newly constructed value. A Map calls Touch every time a value is inserted
+// or re-accessed, Remove every time a key leaves the Map for any reason, and
+// Evict right after the Touch for an insert to ask whether something should
+// now be evicted (a re-access Touch is not followed by Evict).
+//
+// All three methods are only ever called while the owning Map holds its
+// internal lock, so implementations don't need their own synchronization.
+type EvictionPolicy[K comparable, V any] interface {
+	// Touch records that key now holds val in the cache.
+	Touch(key K, val V)
+	// Remove forgets key, wherever it went.
+	Remove(key K)
+	// Evict returns a key that should be evicted, and whether eviction is
+	// necessary at all right now.
+	Evict() (key K, ok bool)
+}
+
+// lruPolicy evicts the least recently touched key once more than maxEntries
+// are tracked.
+type lruPolicy[K comparable, V any] struct {
+	maxEntries int
+	ll         *list.List
+	elems      map[K]*list.Element
+}
+
+// NewLRU returns an EvictionPolicy that keeps at most maxEntries keys,
+// evicting the least recently touched one.
+func NewLRU[K comparable, V any](maxEntries int) EvictionPolicy[K, V] {
+	return &lruPolicy[K, V]{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		elems:      make(map[K]*list.Element),
+	}
+}
+
+func (p *lruPolicy[K, V]) Touch(key K, _ V) {
+	if elem, ok := p.elems[key]; ok {
+		p.ll.MoveToFront(elem)
+		return
+	}
+	p.elems[key] = p.ll.PushFront(key)
+}
+
+func (p *lruPolicy[K, V]) Remove(key K) {
+	elem, ok := p.elems[key]
+	if !ok {
+		return
+	}
+	p.ll.Remove(elem)
+	delete(p.elems, key)
+}
+
+func (p *lruPolicy[K, V]) Evict() (key K, ok bool) {
+	if p.ll.Len() <= p.maxEntries {
+		return key, false
+	}
+	back := p.ll.Back()
+	if back == nil {
+		return key, false
+	}
+	return back.Value.(K), true
+}
+
+// lfuPolicy evicts the least frequently touched key once more than
+// maxEntries are tracked, breaking ties in favor of the oldest entry.
+type lfuPolicy[K comparable, V any] struct {
+	maxEntries int
+	freq       map[K]int
+	order      []K
+}
+
+// NewLFU returns an EvictionPolicy that keeps at most maxEntries keys,
+// evicting the least frequently touched one.
+func NewLFU[K comparable, V any](maxEntries int) EvictionPolicy[K, V] {
+	return &lfuPolicy[K, V]{
+		maxEntries: maxEntries,
+		freq:       make(map[K]int),
+	}
+}
+
+func (p *lfuPolicy[K, V]) Touch(key K, _ V) {
+	if _, ok := p.freq[key]; !ok {
+		p.order = append(p.order, key)
+	}
+	p.freq[key]++
+}
+
+func (p *lfuPolicy[K, V]) Remove(key K) {
+	if _, ok := p.freq[key]; !ok {
+		return
+	}
+	delete(p.freq, key)
+	for i, k := range p.order {
+		if k == key {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *lfuPolicy[K, V]) Evict() (key K, ok bool) {
+	if len(p.freq) <= p.maxEntries {
+		return key, false
+	}
+	victimFreq := -1
+	for _, k := range p.order {
+		f, tracked := p.freq[k]
+		if !tracked {
+			continue
+		}
+		if victimFreq == -1 || f < victimFreq {
+			key, victimFreq = k, f
+		}
+	}
+	return key, victimFreq != -1
+}
+
+// sizeBoundedPolicy evicts keys, oldest first, once the sum of their sizes
+// exceeds maxBytes.
+type sizeBoundedPolicy[K comparable, V any] struct {
+	maxBytes int64
+	sizer    func(V) int64
+	used     int64
+	sizes    map[K]int64
+	order    []K
+}
+
+// NewSizeBounded returns an EvictionPolicy that keeps the sum of sizer(val)
+// across all tracked values at or below maxBytes, evicting the oldest
+// entries first once that bound is exceeded.
+func NewSizeBounded[K comparable, V any](maxBytes int64, sizer func(V) int64) EvictionPolicy[K, V] {
+	return &sizeBoundedPolicy[K, V]{
+		maxBytes: maxBytes,
+		sizer:    sizer,
+		sizes:    make(map[K]int64),
+	}
+}
+
+func (p *sizeBoundedPolicy[K, V]) Touch(key K, val V) {
+	if old, ok := p.sizes[key]; ok {
+		p.used -= old
+	} else {
+		p.order = append(p.order, key)
+	}
+	size := p.sizer(val)
+	p.sizes[key] = size
+	p.used += size
+}
+
+func (p *sizeBoundedPolicy[K, V]) Remove(key K) {
+	size, ok := p.sizes[key]
+	if !ok {
+		return
+	}
+	p.used -= size
+	delete(p.sizes, key)
+	for i, k := range p.order {
+		if k == key {
+			p.order = append(p.order[:i], p.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (p *sizeBoundedPolicy[K, V]) Evict() (key K, ok bool) {
+	if p.used <= p.maxBytes || len(p.order) == 0 {
+		return key, false
+	}
+	return p.order[0], true
+}
@@ -0,0 +1,49 @@
+package lazymap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/waylen888/lazymap"
+)
+
+// Test_SizeBoundedPolicy_EvictsUntilWithinBound reproduces the bug where a
+// single oversized insert left the tracked total far above maxBytes because
+// touchAndEvict only ever consulted Evict once per Touch. A 50-byte insert
+// on top of 90 bytes already cached must evict enough of the old entries to
+// land back at or below the 100-byte bound, not just the single oldest one.
+func Test_SizeBoundedPolicy_EvictsUntilWithinBound(t *testing.T) {
+	const maxBytes = 100
+
+	m := lazymap.New[string, int](0)
+	m.EvictionPolicy = lazymap.NewSizeBounded[string, int](maxBytes, func(v int) int64 { return int64(v) })
+
+	var evictedBytes int64
+	m.OnDelete = func(_ string, v int) {
+		evictedBytes += int64(v)
+	}
+
+	for i := 0; i < 9; i++ {
+		key := string(rune('a' + i))
+		if _, err := m.LoadOrCtor(context.Background(), key, func(ctx context.Context, _ string) (int, error) {
+			return 10, nil
+		}); err != nil {
+			t.Fatalf("LoadOrCtor %s: %v", key, err)
+		}
+	}
+	if evictedBytes != 0 {
+		t.Fatalf("unexpected eviction while under the bound: evicted %d bytes", evictedBytes)
+	}
+
+	if _, err := m.LoadOrCtor(context.Background(), "z", func(ctx context.Context, _ string) (int, error) {
+		return 50, nil
+	}); err != nil {
+		t.Fatalf("LoadOrCtor z: %v", err)
+	}
+
+	const totalInserted = 9*10 + 50
+	remaining := totalInserted - evictedBytes
+	if remaining > maxBytes {
+		t.Fatalf("tracked total %d bytes exceeds maxBytes %d after insert (evicted only %d bytes)", remaining, maxBytes, evictedBytes)
+	}
+}
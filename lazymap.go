@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,24 +20,142 @@ type Map[K comparable, V any] struct {
 	// You can do any cleanup actions.
 	OnDelete func(key K, value V)
 
-	mu sync.Mutex
-	m  map[K]*entity[V]
+	// EvictionPolicy, if set, is consulted every time a constructed value is
+	// inserted into the Map and may name another key to evict to make room.
+	// See NewLRU, NewLFU and NewSizeBounded.
+	EvictionPolicy EvictionPolicy[K, V]
+
+	// RefreshFunc, if set, is used to refresh an entry's value for
+	// RefreshAheadFraction and StaleFor instead of re-running the original
+	// constructor, so a revalidation can be told apart from a first-time
+	// construct (e.g. to skip setup the constructor only does once). Both
+	// modes need it: while RefreshFunc is nil, neither refresh-ahead nor
+	// stale-while-revalidate runs, whatever RefreshAheadFraction and StaleFor
+	// are set to.
+	RefreshFunc func(context.Context, K) (V, error)
+
+	// RefreshAheadFraction, if non-zero, enables refresh-ahead: once a cache
+	// hit lands within this fraction of Lifetime from expiry, a background
+	// refresh is kicked off via RefreshFunc so the entry stays warm instead
+	// of making a later caller stall on a cold miss. Must be in (0, 1].
+	RefreshAheadFraction float64
+
+	// StaleFor, if non-zero, enables stale-while-revalidate: once Lifetime
+	// elapses, the old value keeps being served for up to StaleFor while a
+	// background refresh via RefreshFunc runs, and the entry is only deleted
+	// if that refresh hasn't succeeded by the time StaleFor elapses.
+	StaleFor time.Duration
+
+	// RetryPolicy, if set, governs how a failed constructor call is handled:
+	// retried with backoff, cached as a negative result, or failed outright.
+	// Without one, a failed construction behaves as it always has.
+	RetryPolicy *RetryPolicy
+
+	mu      sync.Mutex
+	m       map[K]*entity[V]
+	stats   statCounters
+	backend Backend[K, V]
+
+	eventMu     sync.Mutex
+	subscribers map[chan Event[K, V]]struct{}
 }
 
 type entity[V any] struct {
-	wg     sync.WaitGroup
-	val    V
-	err    error
-	timer  *time.Timer
-	ctx    context.Context
-	cacenl context.CancelFunc
+	wg sync.WaitGroup
+
+	valMu sync.RWMutex
+	val   V
+
+	err error
+
+	timer     *time.Timer
+	ttl       time.Duration // duration the timer is reset to on a hit
+	expiresAt time.Time
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	refreshing atomic.Bool
+
+	// ctorCtx is the context passed to the constructor. It is derived from
+	// context.Background() rather than any single caller's context, so that
+	// it can be cancelled based on all of the callers who joined it, not just
+	// the one that happened to create the entry.
+	ctorCtx    context.Context
+	ctorCancel context.CancelFunc
+	// doneCh is closed once the constructor has returned, success or not.
+	doneCh chan struct{}
+
+	subMu     sync.Mutex
+	subs      int
+	permanent bool
+}
+
+func (e *entity[V]) getVal() V {
+	e.valMu.RLock()
+	defer e.valMu.RUnlock()
+	return e.val
+}
+
+func (e *entity[V]) setVal(v V) {
+	e.valMu.Lock()
+	e.val = v
+	e.valMu.Unlock()
 }
 
-// New returns a Map with lifetime duration.
-func New[K comparable, V any](lifetime time.Duration) *Map[K, V] {
-	return &Map[K, V]{
+// statCounters holds the atomic counters backing Stats.
+type statCounters struct {
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	dedupJoins    atomic.Uint64
+	ctorErrors    atomic.Uint64
+	evictions     atomic.Uint64
+	droppedEvents atomic.Uint64
+}
+
+// Stats is a point-in-time snapshot of a Map's activity counters.
+type Stats struct {
+	// Hits counts LoadOrCtor/LoadOrCtorShared calls served from an
+	// already-constructed entry.
+	Hits uint64
+	// Misses counts calls that created a new entry, whose value then came
+	// from the Backend (if one is configured and has it) or the constructor.
+	Misses uint64
+	// DedupJoins counts calls that joined a constructor already in flight for
+	// the same key, instead of invoking it themselves.
+	DedupJoins uint64
+	// CtorErrors counts constructions that ended in an error. With a
+	// RetryPolicy, the failed attempts before the final one are not counted.
+	CtorErrors uint64
+	// Evictions counts entries removed via Delete, whether called directly,
+	// by TTL expiry, or by an eviction policy.
+	Evictions uint64
+	// DroppedEvents counts Events that couldn't be delivered because a
+	// Subscribe channel's buffer was full.
+	DroppedEvents uint64
+}
+
+// Stats returns a snapshot of the Map's counters.
+func (m *Map[K, V]) Stats() Stats {
+	return Stats{
+		Hits:          m.stats.hits.Load(),
+		Misses:        m.stats.misses.Load(),
+		DedupJoins:    m.stats.dedupJoins.Load(),
+		CtorErrors:    m.stats.ctorErrors.Load(),
+		Evictions:     m.stats.evictions.Load(),
+		DroppedEvents: m.stats.droppedEvents.Load(),
+	}
+}
+
+// New returns a Map with lifetime duration. See WithBackend for the
+// available options.
+func New[K comparable, V any](lifetime time.Duration, opts ...Option[K, V]) *Map[K, V] {
+	m := &Map[K, V]{
 		Lifetime: lifetime,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 type ctorFunc[K comparable, V any] func(context.Context, K) (V, error)
@@ -44,19 +163,77 @@ type ctorFunc[K comparable, V any] func(context.Context, K) (V, error)
 // ErrCtorNotProvided lazy loading constructor not provided error
 var ErrCtorNotProvided = errors.New("constructor not provided")
 
+// Result is the value/error pair delivered by LoadOrCtorShared.
+type Result[V any] struct {
+	Value V
+	Err   error
+}
+
 // LoadOrCtor returns the value for the key if it exists.
 // Otherwise, it will call the constructor and return its value.
 // If the constructor returns an error, the value will not be stored in the cache.
 func (m *Map[K, V]) LoadOrCtor(ctx context.Context, key K, fn ctorFunc[K, V]) (V, error) {
+	var value V
+	if fn == nil {
+		return value, ErrCtorNotProvided
+	}
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	var value V
+	e, created := m.getOrCreate(key)
+	if created {
+		m.stats.misses.Add(1)
+		m.join(e, ctx)
+		m.construct(key, e, fn)
+		return e.getVal(), e.err
+	}
+
+	if m.joinExisting(key, e, ctx) {
+		e.wg.Wait()
+	}
+	return e.getVal(), e.err
+}
+
+// LoadOrCtorShared behaves like LoadOrCtor, but never blocks the caller.
+// It returns a channel that receives exactly one Result once the value is
+// available, then is closed. Multiple independent callers, each with their
+// own context, may subscribe to the same in-flight (or cached) construction.
+// Once every subscriber's context has been cancelled while the constructor is
+// still running, the constructor's context is cancelled too so it can abort.
+func (m *Map[K, V]) LoadOrCtorShared(ctx context.Context, key K, fn ctorFunc[K, V]) <-chan Result[V] {
+	ch := make(chan Result[V], 1)
 	if fn == nil {
-		return value, ErrCtorNotProvided
+		ch <- Result[V]{Err: ErrCtorNotProvided}
+		close(ch)
+		return ch
 	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	e, created := m.getOrCreate(key)
+	if created {
+		m.stats.misses.Add(1)
+		m.join(e, ctx)
+		go m.construct(key, e, fn)
+	} else {
+		m.joinExisting(key, e, ctx)
+	}
+
+	go func() {
+		e.wg.Wait()
+		ch <- Result[V]{Value: e.getVal(), Err: e.err}
+		close(ch)
+	}()
+	return ch
+}
 
+// getOrCreate returns the entity for key, creating and registering a new one
+// if it doesn't exist yet. created reports whether this call created it, in
+// which case the caller is responsible for running the constructor and
+// calling finishConstruction.
+func (m *Map[K, V]) getOrCreate(key K) (e *entity[V], created bool) {
 	m.mu.Lock()
 	if m.m == nil {
 		m.m = make(map[K]*entity[V])
@@ -64,47 +241,331 @@ func (m *Map[K, V]) LoadOrCtor(ctx context.Context, key K, fn ctorFunc[K, V]) (V
 
 	if e, hit := m.m[key]; hit {
 		if e.timer != nil {
-			e.timer.Reset(m.Lifetime)
+			e.timer.Reset(e.ttl)
+			e.expiresAt = time.Now().Add(e.ttl)
+		}
+		refreshAhead := false
+		if m.EvictionPolicy != nil {
+			select {
+			case <-e.doneCh:
+				m.EvictionPolicy.Touch(key, e.getVal())
+			default:
+			}
+		}
+		select {
+		case <-e.doneCh:
+			refreshAhead = m.dueForRefreshAhead(e)
+		default:
 		}
 		m.mu.Unlock()
-		e.wg.Wait()
-		return e.val, e.err
+		if refreshAhead {
+			m.refreshAhead(key, e)
+		}
+		return e, false
 	}
 
-	e := new(entity[V])
-	// e.ctx only cancelled when entry deleted from Map
-	e.ctx, e.cacenl = context.WithCancel(context.Background())
+	e = new(entity[V])
+	// e.ctx only cancelled when entry deleted from Map.
+	e.ctx, e.cancel = context.WithCancel(context.Background())
+	e.ctorCtx, e.ctorCancel = context.WithCancel(context.Background())
+	e.doneCh = make(chan struct{})
 	e.wg.Add(1)
 	m.m[key] = e
 	m.mu.Unlock()
 
-	e.val, e.err = fn(ctx, key)
+	return e, true
+}
+
+// joinExisting registers ctx against an already-known entity, counting the
+// call as a hit or a dedup-join, and reports whether the entry is still
+// under construction (i.e. whether the caller needs to wait on e.wg).
+func (m *Map[K, V]) joinExisting(key K, e *entity[V], ctx context.Context) bool {
+	select {
+	case <-e.doneCh:
+		m.stats.hits.Add(1)
+		m.emit(Hit, key, e.getVal())
+		return false
+	default:
+		m.stats.dedupJoins.Add(1)
+		m.join(e, ctx)
+		return true
+	}
+}
+
+// join registers ctx as a subscriber of e's in-flight construction, so that
+// e.ctorCtx is cancelled once every subscriber that can be cancelled has
+// gone away. A subscriber whose context can never be cancelled (such as
+// context.Background()) makes the construction uncancellable for as long as
+// it is subscribed.
+func (m *Map[K, V]) join(e *entity[V], ctx context.Context) {
+	if ctx.Done() == nil {
+		e.subMu.Lock()
+		e.permanent = true
+		e.subMu.Unlock()
+		return
+	}
+
+	e.subMu.Lock()
+	e.subs++
+	e.subMu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			e.subMu.Lock()
+			e.subs--
+			cancel := !e.permanent && e.subs <= 0
+			e.subMu.Unlock()
+			if cancel {
+				e.ctorCancel()
+			}
+		case <-e.doneCh:
+		}
+	}()
+}
+
+// construct produces e's value: if a Backend is configured it's consulted
+// first, falling back to fn on a backend miss and persisting the result back
+// to it on success. It always finishes by calling finishConstruction.
+func (m *Map[K, V]) construct(key K, e *entity[V], fn ctorFunc[K, V]) {
+	if m.backend != nil {
+		if val, ttl, ok, err := m.backend.Get(e.ctorCtx, key); err == nil && ok {
+			e.val, e.err = val, nil
+			m.finishConstruction(key, e, ttl)
+			m.emit(Loaded, key, e.getVal())
+			return
+		}
+	}
+
+	var ttl time.Duration
+	if m.RetryPolicy != nil {
+		e.val, e.err, ttl = m.runWithRetry(e.ctorCtx, key, fn)
+	} else {
+		e.val, e.err = fn(e.ctorCtx, key)
+	}
+
+	if e.err == nil {
+		ttl = m.Lifetime
+		if m.backend != nil {
+			// Best-effort: a backend write failure shouldn't fail an
+			// otherwise successful load.
+			_ = m.backend.Put(context.Background(), key, e.val, m.Lifetime)
+		}
+	}
 
+	m.finishConstruction(key, e, ttl)
 	if e.err != nil {
-		m.mu.Lock()
-		delete(m.m, key)
-		m.mu.Unlock()
-	} else if m.Lifetime != 0 {
-		e.timer = time.NewTimer(m.Lifetime)
-		go m.observeEntry(key, e)
+		m.emit(Errored, key, e.getVal())
+	} else {
+		m.emit(Loaded, key, e.getVal())
 	}
+}
 
+// finishConstruction records the constructor's outcome, arms the TTL timer
+// with ttl on success, and wakes up every waiter.
+func (m *Map[K, V]) finishConstruction(key K, e *entity[V], ttl time.Duration) {
+	if e.err != nil {
+		m.stats.ctorErrors.Add(1)
+		if ttl > 0 {
+			// A RetryPolicy classified this error as CacheNegative: keep the
+			// entry so subsequent callers get the error back for ttl
+			// without re-invoking the constructor.
+			m.mu.Lock()
+			e.timer = time.NewTimer(ttl)
+			e.ttl = ttl
+			e.expiresAt = time.Now().Add(ttl)
+			m.mu.Unlock()
+			go m.observeEntry(key, e)
+		} else {
+			m.mu.Lock()
+			delete(m.m, key)
+			if m.EvictionPolicy != nil {
+				m.EvictionPolicy.Remove(key)
+			}
+			m.mu.Unlock()
+		}
+	} else {
+		if ttl != 0 {
+			m.mu.Lock()
+			e.timer = time.NewTimer(ttl)
+			e.ttl = ttl
+			e.expiresAt = time.Now().Add(ttl)
+			m.mu.Unlock()
+			go m.observeEntry(key, e)
+		}
+		if m.EvictionPolicy != nil {
+			for _, victim := range m.touchAndEvict(key, e.val) {
+				m.stats.evictions.Add(1)
+				if m.backend != nil {
+					_ = m.backend.Delete(context.Background(), victim.key)
+				}
+				if m.OnDelete != nil {
+					m.OnDelete(victim.key, victim.val)
+				}
+			}
+		}
+	}
+
+	close(e.doneCh)
+	e.ctorCancel()
 	e.wg.Done()
+}
 
-	return e.val, e.err
+// evictedEntry is one key/value pair touchAndEvict removed from the Map.
+type evictedEntry[K comparable, V any] struct {
+	key K
+	val V
 }
 
+// touchAndEvict records key/val with the EvictionPolicy, then asks it to
+// evict repeatedly until it reports nothing left to do (a single Touch can
+// push a size-bounded policy arbitrarily far over its limit, not just one
+// entry over). It also stops as soon as the policy names key itself: the
+// value just inserted is never evicted, so the Map can stay above the
+// policy's bound (e.g. when key is LFU's least frequently touched entry, or
+// its value alone is larger than a size bound). Every evicted key is removed
+// from the Map and returned so the caller can sync the backend and fire
+// OnDelete outside the lock.
+func (m *Map[K, V]) touchAndEvict(key K, val V) []evictedEntry[K, V] {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.EvictionPolicy.Touch(key, val)
+
+	var victims []evictedEntry[K, V]
+	for {
+		vk, ok := m.EvictionPolicy.Evict()
+		if !ok || vk == key {
+			break
+		}
+		ve, exists := m.m[vk]
+		if !exists {
+			// Policy is tracking a key the Map already forgot; drop it from
+			// the policy too so Evict doesn't loop on it forever.
+			m.EvictionPolicy.Remove(vk)
+			continue
+		}
+
+		ve.cancel()
+		delete(m.m, vk)
+		m.EvictionPolicy.Remove(vk)
+		victims = append(victims, evictedEntry[K, V]{key: vk, val: ve.getVal()})
+	}
+
+	return victims
+}
+
+// dueForRefreshAhead reports whether e has landed within
+// RefreshAheadFraction of Lifetime from expiry, and refresh-ahead should be
+// kicked off. Must be called while holding m.mu.
+func (m *Map[K, V]) dueForRefreshAhead(e *entity[V]) bool {
+	if m.RefreshFunc == nil || m.RefreshAheadFraction <= 0 || m.Lifetime == 0 {
+		return false
+	}
+	remaining := time.Until(e.expiresAt)
+	if remaining <= 0 {
+		return false
+	}
+	return remaining <= time.Duration(float64(m.Lifetime)*m.RefreshAheadFraction)
+}
+
+// refreshAhead runs at most one background refresh of e's value via
+// RefreshFunc at a time, swapping the new value in on success without
+// blocking any caller.
+func (m *Map[K, V]) refreshAhead(key K, e *entity[V]) {
+	if !e.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer e.refreshing.Store(false)
+		val, err := m.RefreshFunc(context.Background(), key)
+		if err != nil {
+			return
+		}
+		e.setVal(val)
+		m.mu.Lock()
+		if e.timer != nil {
+			e.timer.Reset(m.Lifetime)
+		}
+		e.ttl = m.Lifetime
+		e.expiresAt = time.Now().Add(m.Lifetime)
+		m.mu.Unlock()
+		m.emit(Refreshed, key, val)
+	}()
+}
+
+// observeEntry waits for e's TTL timer to fire or e.ctx to be cancelled,
+// handling each expiry in turn. A stale-while-revalidate refresh rearms the
+// same timer rather than starting a new watcher, so this loops in place
+// instead of recursing via a new goroutine per cycle.
 func (m *Map[K, V]) observeEntry(key K, e *entity[V]) {
+	for {
+		select {
+		case <-e.timer.C:
+			if m.handleExpiry(key, e) {
+				continue
+			}
+			return
+		case <-e.ctx.Done():
+			e.timer.Stop()
+			return
+		}
+	}
+}
+
+// handleExpiry runs once e's TTL elapses. With stale-while-revalidate
+// enabled (StaleFor and RefreshFunc both set), it keeps serving the stale
+// value for up to StaleFor while a background refresh runs via RefreshFunc,
+// only deleting the entry if that refresh hasn't succeeded in time.
+// Otherwise it deletes the entry immediately, as before. It reports whether
+// e is still alive and its timer rearmed, so observeEntry knows whether to
+// keep watching it.
+func (m *Map[K, V]) handleExpiry(key K, e *entity[V]) bool {
+	if m.RefreshFunc == nil || m.StaleFor <= 0 {
+		m.deleteWithReason(key, Expired)
+		return false
+	}
+
+	refreshed := make(chan bool, 1)
+	go func() {
+		val, err := m.RefreshFunc(context.Background(), key)
+		if err != nil {
+			refreshed <- false
+			return
+		}
+		e.setVal(val)
+		refreshed <- true
+	}()
+
 	select {
-	case <-e.timer.C:
-		m.Delete(key)
+	case ok := <-refreshed:
+		if !ok {
+			m.deleteWithReason(key, Expired)
+			return false
+		}
+		m.mu.Lock()
+		e.ttl = m.Lifetime
+		e.expiresAt = time.Now().Add(m.Lifetime)
+		m.mu.Unlock()
+		e.timer.Reset(m.Lifetime)
+		m.emit(Refreshed, key, e.getVal())
+		return true
+	case <-time.After(m.StaleFor):
+		m.deleteWithReason(key, Expired)
+		return false
 	case <-e.ctx.Done():
+		return false
 	}
-	e.timer.Stop()
 }
 
 // Delete deletes the value for a key.
 func (m *Map[K, V]) Delete(key K) {
+	m.deleteWithReason(key, Deleted)
+}
+
+// deleteWithReason implements Delete and TTL-driven removal, emitting kind
+// (Deleted or Expired) so subscribers can tell them apart.
+func (m *Map[K, V]) deleteWithReason(key K, kind EventKind) {
 	m.mu.Lock()
 
 	e, exist := m.m[key]
@@ -113,11 +574,21 @@ func (m *Map[K, V]) Delete(key K) {
 		return
 	}
 
-	e.cacenl()
+	e.cancel()
 	delete(m.m, key)
+	if m.EvictionPolicy != nil {
+		m.EvictionPolicy.Remove(key)
+	}
 	m.mu.Unlock()
 
+	if m.backend != nil {
+		_ = m.backend.Delete(context.Background(), key)
+	}
+
+	m.stats.evictions.Add(1)
+
 	if m.OnDelete != nil {
-		m.OnDelete(key, e.val)
+		m.OnDelete(key, e.getVal())
 	}
+	m.emit(kind, key, e.getVal())
 }
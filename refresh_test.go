@@ -0,0 +1,59 @@
+package lazymap_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/waylen888/lazymap"
+)
+
+// Test_StaleWhileRevalidate_ExpiresAfterSecondRefresh reproduces a bug where a
+// successful stale-while-revalidate refresh rearmed the entry's timer but
+// spawned a second observeEntry goroutine to watch it, while the original
+// observeEntry call (the one that triggered the refresh) went on to stop
+// that same timer. The entry then never expired again: the second refresh
+// cycle never ran and the background watcher goroutine leaked. This drives
+// an entry through two TTL cycles that each end in a successful refresh and
+// asserts it is still torn down (via OnDelete) on the third expiry, when the
+// refresh fails, not stuck alive forever after the first refresh.
+func Test_StaleWhileRevalidate_ExpiresAfterSecondRefresh(t *testing.T) {
+	const lifetime = 30 * time.Millisecond
+
+	m := lazymap.New[string, string](lifetime)
+	m.StaleFor = lifetime
+
+	var refreshCalls atomic.Int32
+	m.RefreshFunc = func(_ context.Context, _ string) (string, error) {
+		n := refreshCalls.Add(1)
+		if n <= 2 {
+			return fmt.Sprintf("refreshed-%d", n), nil
+		}
+		return "", errors.New("stop refreshing")
+	}
+
+	deleted := make(chan struct{})
+	m.OnDelete = func(_ string, _ string) {
+		close(deleted)
+	}
+
+	_, err := m.LoadOrCtor(context.Background(), "k", func(_ context.Context, _ string) (string, error) {
+		return "initial", nil
+	})
+	if err != nil {
+		t.Fatalf("LoadOrCtor: %v", err)
+	}
+
+	select {
+	case <-deleted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("entry was never deleted after its second refresh; stale-while-revalidate refresh must have stopped the timer early")
+	}
+
+	if got := refreshCalls.Load(); got < 3 {
+		t.Fatalf("RefreshFunc called %d times, want at least 3 (two successful refreshes plus the failing one that ends it)", got)
+	}
+}
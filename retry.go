@@ -0,0 +1,112 @@
+package lazymap
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+type retryAction int
+
+const (
+	actionFail retryAction = iota
+	actionRetry
+	actionCacheNegative
+)
+
+// RetryDecision tells a Map's RetryPolicy what to do after a constructor
+// call returns an error. See Fail, Retry and CacheNegative.
+type RetryDecision struct {
+	action      retryAction
+	negativeTTL time.Duration
+}
+
+// Fail returns the error to the caller immediately and does not cache the
+// entry, exactly as a Map with no RetryPolicy behaves today.
+var Fail = RetryDecision{action: actionFail}
+
+// Retry re-invokes the constructor after an exponential backoff with full
+// jitter, up to RetryPolicy.MaxAttempts times.
+var Retry = RetryDecision{action: actionRetry}
+
+// CacheNegative caches the error itself for ttl, so that callers within that
+// window get the error back without the constructor running again.
+func CacheNegative(ttl time.Duration) RetryDecision {
+	return RetryDecision{action: actionCacheNegative, negativeTTL: ttl}
+}
+
+// RetryPolicy configures how a Map responds to constructor errors. Without
+// one, a failed construction behaves as it always has: the error is
+// returned and nothing is cached.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of constructor calls to make,
+	// including the first, before giving up on an error classified as Retry.
+	// Zero means retry forever.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry. It doubles after
+	// every subsequent retry, capped at MaxBackoff.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential backoff. Zero means unlimited.
+	MaxBackoff time.Duration
+	// Jitter is the fraction (0 to 1) of the backoff to randomize: the
+	// actual delay is chosen uniformly from [0, backoff*Jitter]. Zero
+	// disables jitter and uses the backoff as-is.
+	Jitter float64
+
+	// Classify decides what to do with a constructor error. If nil, every
+	// error is treated as Fail.
+	Classify func(error) RetryDecision
+}
+
+// runWithRetry calls fn, retrying with exponential backoff and jitter per
+// m.RetryPolicy when the error is classified as Retry. It returns the final
+// value/error, and the TTL the entry should be cached with if err is
+// non-nil: 0 means don't cache it (Fail, or retries exhausted), non-zero is
+// the CacheNegative TTL.
+func (m *Map[K, V]) runWithRetry(ctx context.Context, key K, fn ctorFunc[K, V]) (val V, err error, negativeTTL time.Duration) {
+	policy := m.RetryPolicy
+	backoff := policy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		val, err = fn(ctx, key)
+		if err == nil {
+			return val, nil, 0
+		}
+
+		decision := Fail
+		if policy.Classify != nil {
+			decision = policy.Classify(err)
+		}
+
+		switch decision.action {
+		case actionCacheNegative:
+			return val, err, decision.negativeTTL
+		case actionRetry:
+			if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+				return val, err, 0
+			}
+			select {
+			case <-time.After(fullJitter(backoff, policy.Jitter)):
+			case <-ctx.Done():
+				return val, ctx.Err(), 0
+			}
+			backoff *= 2
+			if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+				backoff = policy.MaxBackoff
+			}
+		default: // actionFail
+			return val, err, 0
+		}
+	}
+}
+
+func fullJitter(backoff time.Duration, jitter float64) time.Duration {
+	if backoff <= 0 || jitter <= 0 {
+		return backoff
+	}
+	if jitter > 1 {
+		jitter = 1
+	}
+	return time.Duration(rand.Float64() * jitter * float64(backoff))
+}
@@ -0,0 +1,142 @@
+package lazymap_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/waylen888/lazymap"
+)
+
+func Test_RetryPolicy_RetriesUntilSuccess(t *testing.T) {
+	m := lazymap.New[string, string](0)
+	m.RetryPolicy = &lazymap.RetryPolicy{
+		MaxAttempts: 5,
+		Classify: func(error) lazymap.RetryDecision {
+			return lazymap.Retry
+		},
+	}
+
+	var attempts atomic.Int32
+	val, err := m.LoadOrCtor(context.Background(), "k", func(_ context.Context, _ string) (string, error) {
+		n := attempts.Add(1)
+		if n < 3 {
+			return "", errors.New("not yet")
+		}
+		return "ok", nil
+	})
+	if err != nil {
+		t.Fatalf("LoadOrCtor: %v", err)
+	}
+	if val != "ok" {
+		t.Fatalf("got %q", val)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+}
+
+func Test_RetryPolicy_StopsAtMaxAttempts(t *testing.T) {
+	m := lazymap.New[string, string](0)
+	m.RetryPolicy = &lazymap.RetryPolicy{
+		MaxAttempts: 3,
+		Classify: func(error) lazymap.RetryDecision {
+			return lazymap.Retry
+		},
+	}
+
+	var attempts atomic.Int32
+	wantErr := errors.New("always fails")
+	_, err := m.LoadOrCtor(context.Background(), "k", func(_ context.Context, _ string) (string, error) {
+		attempts.Add(1)
+		return "", wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Fatalf("attempts = %d, want exactly MaxAttempts (3)", got)
+	}
+}
+
+func Test_RetryPolicy_CacheNegativeSuppressesReinvocation(t *testing.T) {
+	m := lazymap.New[string, string](0)
+	m.RetryPolicy = &lazymap.RetryPolicy{
+		Classify: func(error) lazymap.RetryDecision {
+			return lazymap.CacheNegative(time.Hour)
+		},
+	}
+
+	var attempts atomic.Int32
+	wantErr := errors.New("bad input")
+	ctor := func(_ context.Context, _ string) (string, error) {
+		attempts.Add(1)
+		return "", wantErr
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := m.LoadOrCtor(context.Background(), "k", ctor)
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("call %d: err = %v, want %v", i, err, wantErr)
+		}
+	}
+
+	if got := attempts.Load(); got != 1 {
+		t.Fatalf("ctor invoked %d times, want 1 (negative cache should suppress the rest)", got)
+	}
+}
+
+func Test_RetryPolicy_FailReturnsImmediately(t *testing.T) {
+	m := lazymap.New[string, string](0)
+	m.RetryPolicy = &lazymap.RetryPolicy{
+		Classify: func(error) lazymap.RetryDecision {
+			return lazymap.Fail
+		},
+	}
+
+	var attempts atomic.Int32
+	wantErr := errors.New("fatal")
+	_, err := m.LoadOrCtor(context.Background(), "k", func(_ context.Context, _ string) (string, error) {
+		attempts.Add(1)
+		return "", wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("err = %v, want %v", err, wantErr)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Fatalf("attempts = %d, want 1", got)
+	}
+}
+
+func Test_RetryPolicy_CtxCancelMidBackoffReturnsPromptly(t *testing.T) {
+	m := lazymap.New[string, string](0)
+	m.RetryPolicy = &lazymap.RetryPolicy{
+		InitialBackoff: time.Hour,
+		Classify: func(error) lazymap.RetryDecision {
+			return lazymap.Retry
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.LoadOrCtor(ctx, "k", func(_ context.Context, _ string) (string, error) {
+			return "", errors.New("always fails")
+		})
+		done <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("err = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("LoadOrCtor didn't return promptly after ctx cancellation mid-backoff")
+	}
+}
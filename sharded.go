@@ -0,0 +1,96 @@
+package lazymap
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// Hasher computes a shard hash for a key. Use WithHasher to provide one for
+// key types where the default (fmt.Sprintf + FNV-1a) isn't precise or fast
+// enough.
+type Hasher[K comparable] func(key K) uint64
+
+// ShardedOption configures a Sharded map.
+type ShardedOption[K comparable] func(*shardedConfig[K])
+
+type shardedConfig[K comparable] struct {
+	hasher Hasher[K]
+}
+
+// WithHasher overrides the default hash function Sharded uses to pick a
+// shard for a key.
+func WithHasher[K comparable](h Hasher[K]) ShardedOption[K] {
+	return func(c *shardedConfig[K]) {
+		c.hasher = h
+	}
+}
+
+func defaultHasher[K comparable]() Hasher[K] {
+	return func(key K) uint64 {
+		h := fnv.New64a()
+		if s, ok := any(key).(string); ok {
+			h.Write([]byte(s))
+		} else {
+			fmt.Fprintf(h, "%v", key)
+		}
+		return h.Sum64()
+	}
+}
+
+// Sharded is a drop-in replacement for Map's LoadOrCtor, Delete and OnDelete
+// that spreads keys across several independently-locked shards, trading a
+// single mutex for many smaller ones so that LoadOrCtor calls for different
+// keys don't serialize behind each other. The rest of Map's API
+// (LoadOrCtorShared, Stats, Subscribe, and the eviction, refresh, retry and
+// backend settings) is not available on it.
+type Sharded[K comparable, V any] struct {
+	// OnDelete, if set, is called whenever a value is deleted from any shard.
+	OnDelete func(key K, value V)
+
+	shards []*Map[K, V]
+	hasher Hasher[K]
+}
+
+// NewSharded returns a Sharded map with the given lifetime, split across
+// shards independently-locked sub-maps. shards is clamped to at least 1.
+func NewSharded[K comparable, V any](lifetime time.Duration, shards int, opts ...ShardedOption[K]) *Sharded[K, V] {
+	if shards < 1 {
+		shards = 1
+	}
+
+	cfg := shardedConfig[K]{hasher: defaultHasher[K]()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	s := &Sharded[K, V]{
+		shards: make([]*Map[K, V], shards),
+		hasher: cfg.hasher,
+	}
+	for i := range s.shards {
+		shard := New[K, V](lifetime)
+		shard.OnDelete = func(key K, value V) {
+			if s.OnDelete != nil {
+				s.OnDelete(key, value)
+			}
+		}
+		s.shards[i] = shard
+	}
+	return s
+}
+
+func (s *Sharded[K, V]) shardFor(key K) *Map[K, V] {
+	return s.shards[s.hasher(key)%uint64(len(s.shards))]
+}
+
+// LoadOrCtor behaves like Map.LoadOrCtor, routed to the shard owning key.
+func (s *Sharded[K, V]) LoadOrCtor(ctx context.Context, key K, fn ctorFunc[K, V]) (V, error) {
+	return s.shardFor(key).LoadOrCtor(ctx, key, fn)
+}
+
+// Delete behaves like Map.Delete, routed to the shard owning key.
+func (s *Sharded[K, V]) Delete(key K) {
+	s.shardFor(key).Delete(key)
+}
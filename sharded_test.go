@@ -0,0 +1,88 @@
+package lazymap_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/waylen888/lazymap"
+)
+
+func Test_Sharded_DistributesKeysAcrossShards(t *testing.T) {
+	s := lazymap.NewSharded[int, string](0, 4, lazymap.WithHasher(func(key int) uint64 {
+		return uint64(key)
+	}))
+
+	for i := 0; i < 4; i++ {
+		key := i
+		val, err := s.LoadOrCtor(context.Background(), key, func(_ context.Context, _ int) (string, error) {
+			return "ok", nil
+		})
+		if err != nil {
+			t.Fatalf("LoadOrCtor %d: %v", key, err)
+		}
+		if val != "ok" {
+			t.Fatalf("LoadOrCtor %d: got %q", key, val)
+		}
+	}
+}
+
+func Test_Sharded_CustomHasherPicksTheNamedShard(t *testing.T) {
+	const shards = 4
+	var hashed int
+	s := lazymap.NewSharded[string, string](0, shards, lazymap.WithHasher(func(key string) uint64 {
+		hashed++
+		// Route every key to shard 2, regardless of key content.
+		return 2
+	}))
+
+	for _, key := range []string{"a", "b", "c"} {
+		if _, err := s.LoadOrCtor(context.Background(), key, func(_ context.Context, _ string) (string, error) {
+			return "v", nil
+		}); err != nil {
+			t.Fatalf("LoadOrCtor %s: %v", key, err)
+		}
+	}
+
+	if hashed == 0 {
+		t.Fatal("custom Hasher was never called")
+	}
+
+	// Deleting via the same Hasher must find the keys again on shard 2.
+	s.Delete("a")
+	val, err := s.LoadOrCtor(context.Background(), "a", func(_ context.Context, _ string) (string, error) {
+		return "reconstructed", nil
+	})
+	if err != nil {
+		t.Fatalf("LoadOrCtor after delete: %v", err)
+	}
+	if val != "reconstructed" {
+		t.Fatalf("Delete didn't reach the key's shard: got %q", val)
+	}
+}
+
+func Test_Sharded_OnDeleteFansOutFromShards(t *testing.T) {
+	s := lazymap.NewSharded[string, string](0, 4)
+
+	deleted := make(chan string, 1)
+	s.OnDelete = func(key string, _ string) {
+		deleted <- key
+	}
+
+	if _, err := s.LoadOrCtor(context.Background(), "k", func(_ context.Context, _ string) (string, error) {
+		return "v", nil
+	}); err != nil {
+		t.Fatalf("LoadOrCtor: %v", err)
+	}
+
+	s.Delete("k")
+
+	select {
+	case key := <-deleted:
+		if key != "k" {
+			t.Fatalf("OnDelete got key %q, want %q", key, "k")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnDelete was never called")
+	}
+}
@@ -0,0 +1,127 @@
+package lazymap_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/waylen888/lazymap"
+)
+
+func Test_LoadOrCtorShared_DedupsConcurrentCallers(t *testing.T) {
+	m := lazymap.New[string, int](0)
+
+	var ctorCalls atomic.Int32
+	block := make(chan struct{})
+	ctor := func(_ context.Context, _ string) (int, error) {
+		ctorCalls.Add(1)
+		<-block
+		return 42, nil
+	}
+
+	const callers = 5
+	results := make([]<-chan lazymap.Result[int], callers)
+	for i := 0; i < callers; i++ {
+		results[i] = m.LoadOrCtorShared(context.Background(), "k", ctor)
+	}
+	close(block)
+
+	for i, ch := range results {
+		res := <-ch
+		if res.Err != nil {
+			t.Fatalf("caller %d: unexpected error %v", i, res.Err)
+		}
+		if res.Value != 42 {
+			t.Fatalf("caller %d: got %d, want 42", i, res.Value)
+		}
+	}
+
+	if got := ctorCalls.Load(); got != 1 {
+		t.Fatalf("constructor invoked %d times, want 1 (the rest should have deduped)", got)
+	}
+
+	stats := m.Stats()
+	if stats.Misses != 1 {
+		t.Fatalf("Stats().Misses = %d, want 1", stats.Misses)
+	}
+	if stats.DedupJoins != callers-1 {
+		t.Fatalf("Stats().DedupJoins = %d, want %d", stats.DedupJoins, callers-1)
+	}
+}
+
+func Test_LoadOrCtorShared_CancelsConstructorOnlyAfterLastSubscriberLeaves(t *testing.T) {
+	m := lazymap.New[string, int](0)
+
+	ctorStarted := make(chan struct{})
+	ctorCtxDone := make(chan struct{})
+	ctor := func(ctx context.Context, _ string) (int, error) {
+		close(ctorStarted)
+		<-ctx.Done()
+		close(ctorCtxDone)
+		return 0, ctx.Err()
+	}
+
+	ctx1, cancel1 := context.WithCancel(context.Background())
+	ctx2, cancel2 := context.WithCancel(context.Background())
+
+	ch1 := m.LoadOrCtorShared(ctx1, "k", ctor)
+	<-ctorStarted
+	ch2 := m.LoadOrCtorShared(ctx2, "k", ctor)
+
+	cancel1()
+	select {
+	case <-ctorCtxDone:
+		t.Fatal("constructor context was cancelled after only one of two subscribers left")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	cancel2()
+	select {
+	case <-ctorCtxDone:
+	case <-time.After(time.Second):
+		t.Fatal("constructor context was never cancelled after the last subscriber left")
+	}
+
+	res1 := <-ch1
+	res2 := <-ch2
+	if !errors.Is(res1.Err, context.Canceled) {
+		t.Fatalf("ch1 err = %v, want context.Canceled", res1.Err)
+	}
+	if !errors.Is(res2.Err, context.Canceled) {
+		t.Fatalf("ch2 err = %v, want context.Canceled", res2.Err)
+	}
+}
+
+func Test_LoadOrCtorShared_BackgroundSubscriberKeepsConstructorAlive(t *testing.T) {
+	m := lazymap.New[string, int](0)
+
+	ctorStarted := make(chan struct{})
+	unblock := make(chan struct{})
+	ctor := func(_ context.Context, _ string) (int, error) {
+		close(ctorStarted)
+		<-unblock
+		return 7, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch1 := m.LoadOrCtorShared(ctx, "k", ctor)
+	<-ctorStarted
+	// A subscriber with an uncancellable context (e.g. Background) makes the
+	// construction permanent: cancelling every cancellable subscriber must
+	// not cancel the constructor.
+	ch2 := m.LoadOrCtorShared(context.Background(), "k", ctor)
+
+	cancel()
+	close(unblock)
+
+	res1 := <-ch1
+	res2 := <-ch2
+	if res1.Err != nil || res1.Value != 7 {
+		t.Fatalf("ch1 = %+v, want value 7 and no error", res1)
+	}
+	if res2.Err != nil || res2.Value != 7 {
+		t.Fatalf("ch2 = %+v, want value 7 and no error", res2)
+	}
+}